@@ -73,6 +73,65 @@ func (e ErrInvEOF) Error() string {
 	return fmt.Sprintln("error occurred during tokenization")
 }
 
-func isEOF(err error) bool {
-	return errors.As(err, &ErrInvEOF{})
+// ErrInvAttributeContext is raised by Validator.StrictSecurity when an
+// attribute value is unsafe for the AttributeContext it's rendered into
+// (e.g. a `javascript:` URL in an href, or an unallowlisted `on*` handler).
+type ErrInvAttributeContext struct {
+	ErrorDetails
+	Context AttributeContext
+	Message string
 }
+
+func (e ErrInvAttributeContext) Error() string {
+	return fmt.Sprintf("invalid attribute value '%s' in attribute '%s' in tag '%s': %s", e.AttributeValue, e.AttributeName, e.TagName, e.Message)
+}
+
+func newAttributeContextError(tagName string, attrName string, attrValue string, ctx AttributeContext, reason string) ValidationError {
+	return ErrInvAttributeContext{
+		ErrorDetails: ErrorDetails{TagName: tagName, AttributeName: attrName, AttributeValue: attrValue, Reason: InvAttributeContext},
+		Context:      ctx,
+		Message:      reason,
+	}
+}
+
+// ErrInvStructuralRule is raised when an element matches a registered
+// StructuralRule's selector but fails its require/forbid/depth/parent
+// constraints.
+type ErrInvStructuralRule struct {
+	ErrorDetails
+	Selector string
+	Message  string
+}
+
+func (e ErrInvStructuralRule) Error() string {
+	return fmt.Sprintf("tag '%s' violates structural rule '%s': %s", e.TagName, e.Selector, e.Message)
+}
+
+func newStructuralRuleError(tagName string, selector string, reason string) ValidationError {
+	return ErrInvStructuralRule{
+		ErrorDetails: ErrorDetails{TagName: tagName, Reason: InvStructuralRule},
+		Selector:     selector,
+		Message:      reason,
+	}
+}
+
+// ErrInvLimitExceeded is raised by Walk when a Validator.MaxTokens or
+// Validator.MaxDepth budget is exceeded, so a hostile or pathological
+// document (e.g. billion-laughs-style deeply nested tag soup) is rejected
+// before it can exhaust memory.
+type ErrInvLimitExceeded struct {
+	ErrorDetails
+	Message string
+}
+
+func (e ErrInvLimitExceeded) Error() string {
+	return fmt.Sprintf("validation limit exceeded: %s", e.Message)
+}
+
+func newLimitExceededError(message string) ValidationError {
+	return ErrInvLimitExceeded{
+		ErrorDetails: ErrorDetails{Reason: InvLimitExceeded},
+		Message:      message,
+	}
+}
+
@@ -1,16 +1,19 @@
 package htmlcheck
 
 import (
+	"bytes"
 	"encoding/json"
-	errorsPkg "errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net/url"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
 
-	"golang.org/x/net/html"
+	"sigs.k8s.io/yaml"
 )
 
 type ErrorReason int
@@ -22,8 +25,46 @@ const (
 	InvNotProperlyClosed   ErrorReason = 3
 	InvDuplicatedAttribute ErrorReason = 4
 	InvEOF                 ErrorReason = 5
+	InvAttributeContext    ErrorReason = 6
+	InvStructuralRule      ErrorReason = 7
+	InvLimitExceeded       ErrorReason = 8
 )
 
+// AttributeContext describes the kind of content an attribute value is
+// rendered into, so StrictSecurity can apply the right checks to it.
+type AttributeContext int
+
+const (
+	contextUnset AttributeContext = iota // not set on the Attribute, fall back to inference
+	PlainContext
+	URLContext
+	JSHandlerContext
+	CSSContext
+	HTMLContext
+)
+
+// contextByAttrName is the default inference table used when an Attribute
+// doesn't set its own Context. `on*` handlers are matched by prefix instead.
+var contextByAttrName = map[string]AttributeContext{
+	"href":       URLContext,
+	"src":        URLContext,
+	"action":     URLContext,
+	"formaction": URLContext,
+	"poster":     URLContext,
+	"cite":       URLContext,
+	"style":      CSSContext,
+}
+
+func inferAttributeContext(attrName string) AttributeContext {
+	if strings.HasPrefix(attrName, "on") {
+		return JSHandlerContext
+	}
+	if ctx, ok := contextByAttrName[attrName]; ok {
+		return ctx
+	}
+	return PlainContext
+}
+
 type ErrorCallback func(tagName string, attributeName string, value string, reason ErrorReason) error
 
 type TagGroup struct {
@@ -38,8 +79,9 @@ type AttributeValue struct {
 }
 
 type Attribute struct {
-	Name  string
-	Value *AttributeValue
+	Name    string
+	Value   *AttributeValue
+	Context AttributeContext
 }
 
 type ValidTag struct {
@@ -52,8 +94,10 @@ type ValidTag struct {
 }
 
 type TagsFile struct {
-	Groups []*TagGroup
-	Tags   []*ValidTag
+	Presets []string
+	Groups  []*TagGroup
+	Tags    []*ValidTag
+	Rules   []StructuralRule
 }
 
 type Validator struct {
@@ -63,6 +107,40 @@ type Validator struct {
 	StopAfterFirstError  bool
 	validTags            map[string]*ValidTag
 	validGroups          map[string]*TagGroup
+
+	// StrictSecurity enables contextual validation of attribute values that
+	// are rendered as URLs, inline JS handlers or CSS, rejecting the ones
+	// known to enable script injection (see AttributeContext).
+	StrictSecurity bool
+	// AllowDataURLs opts back into `data:` URLs in URLContext attributes,
+	// which StrictSecurity rejects by default.
+	AllowDataURLs bool
+
+	allowedJSHandlers map[string]bool
+
+	structuralRules []*compiledStructuralRule
+	selectorCache   map[string]*compiledSelector
+
+	// MaxTokens aborts Walk with ErrInvLimitExceeded once more than this
+	// many tokens have been read from the document. Zero means unbounded.
+	MaxTokens int
+	// MaxDepth aborts Walk with ErrInvLimitExceeded once an element is
+	// nested deeper than this. Zero means unbounded.
+	MaxDepth int
+}
+
+// AllowJSHandler allowlists a specific inline event handler attribute (e.g.
+// "onclick") on a tag so StrictSecurity no longer rejects it outright. Pass
+// "" as tagName to allow the attribute on every tag.
+func (v *Validator) AllowJSHandler(tagName string, attrName string) {
+	if v.allowedJSHandlers == nil {
+		v.allowedJSHandlers = map[string]bool{}
+	}
+	v.allowedJSHandlers[tagName+"|"+attrName] = true
+}
+
+func (v *Validator) isJSHandlerAllowed(tagName string, attrName string) bool {
+	return v.allowedJSHandlers[tagName+"|"+attrName] || v.allowedJSHandlers["|"+attrName]
 }
 
 func (v *Validator) AddValidTags(validTags []*ValidTag) {
@@ -80,7 +158,11 @@ func (v *Validator) AddValidTags(validTags []*ValidTag) {
 		if tag.IsSelfClosing {
 			v.validSelfClosingTags[tag.Name] = true
 		}
-		v.validTagMap[tag.Name] = make(map[string]Attribute, 0)
+		// Re-adding a tag name (e.g. from a second preset) merges into its
+		// existing attribute map instead of discarding what was there.
+		if v.validTagMap[tag.Name] == nil {
+			v.validTagMap[tag.Name] = make(map[string]Attribute, 0)
+		}
 		for _, a := range tag.Attrs {
 			v.validTagMap[tag.Name][a.Name] = a
 		}
@@ -90,7 +172,33 @@ func (v *Validator) AddValidTags(validTags []*ValidTag) {
 				log.Println("second global tag")
 			}
 		}
-		v.validTags[tag.Name] = tag
+		// Re-adding a tag name (e.g. from a second preset) merges Groups and
+		// the self-closing/regex fields into a copy of the existing tag
+		// instead of replacing it, so group membership registered by an
+		// earlier preset isn't silently dropped by a later one composed on
+		// top. The copy keeps the *ValidTag passed in earlier (which may be
+		// shared by a built-in preset) unmodified.
+		if prev, ok := v.validTags[tag.Name]; ok {
+			existing := *prev
+			existing.Groups = slices.Clone(prev.Groups)
+			v.validTags[tag.Name] = &existing
+			for _, groupName := range tag.Groups {
+				if !existing.HasGroup(groupName) {
+					existing.Groups = append(existing.Groups, groupName)
+				}
+			}
+			if tag.IsSelfClosing {
+				existing.IsSelfClosing = true
+			}
+			if tag.AttrRegex != "" {
+				existing.AttrRegex = tag.AttrRegex
+			}
+			if tag.AttrStartsWith != "" {
+				existing.AttrStartsWith = tag.AttrStartsWith
+			}
+		} else {
+			v.validTags[tag.Name] = tag
+		}
 
 		for _, groupName := range tag.Groups {
 			group := v.validGroups[groupName]
@@ -152,22 +260,74 @@ func (v *Validator) IsValidSelfClosingTag(tagName string) bool {
 	return ok
 }
 
+// LoadTagsFromFile loads a JSON or YAML tags file, picking the format from
+// the file's extension (".yaml"/".yml" for YAML, anything else is treated
+// as JSON).
 func (v *Validator) LoadTagsFromFile(path string) error {
 	content, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	tagFile := TagsFile{}
-	err = json.Unmarshal(content, &tagFile)
+	return v.LoadTagsFrom(bytes.NewReader(content), formatFromExt(path))
+}
+
+func formatFromExt(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
+
+// LoadTagsFromString loads a tags file from an in-memory string, e.g. one
+// obtained via go:embed. format is "json" or "yaml".
+func (v *Validator) LoadTagsFromString(content string, format string) error {
+	return v.LoadTagsFrom(strings.NewReader(content), format)
+}
 
+// LoadTagsFrom loads a tags file from r. format is "json" or "yaml"; YAML
+// content is converted to JSON first and unmarshaled into the same
+// TagsFile struct.
+func (v *Validator) LoadTagsFrom(r io.Reader, format string) error {
+	content, err := io.ReadAll(r)
 	if err != nil {
 		return err
 	}
 
+	switch format {
+	case "yaml":
+		content, err = yaml.YAMLToJSON(content)
+		if err != nil {
+			return err
+		}
+	case "json":
+		// already JSON
+	default:
+		return fmt.Errorf("htmlcheck: unsupported tag file format '%s'", format)
+	}
+
+	tagFile := TagsFile{}
+	if err := json.Unmarshal(content, &tagFile); err != nil {
+		return err
+	}
+
+	for _, presetName := range tagFile.Presets {
+		if err := v.UsePreset(presetName); err != nil {
+			return err
+		}
+	}
+
 	v.AddGroups(tagFile.Groups)
 	v.AddValidTags(tagFile.Tags)
 
+	for _, rule := range tagFile.Rules {
+		if err := v.AddStructuralRule(rule); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -224,6 +384,92 @@ func (v *Validator) validateAttributeValue(tagName string, attr Attribute, attrV
 	return ErrInvAttributeValue{ErrorDetails{TagName: tagName, AttributeName: attr.Name, AttributeValue: attrValue}}
 }
 
+// contextFor resolves the AttributeContext that applies to attrName on
+// tagName, preferring an explicit Attribute.Context over the default
+// inference table.
+func (v *Validator) contextFor(tagName string, attrName string) AttributeContext {
+	if attrs, ok := v.validTagMap[tagName]; ok {
+		if attr, ok := attrs[attrName]; ok && attr.Context != contextUnset {
+			return attr.Context
+		}
+	}
+
+	//check global attributes
+	if gAttrs, ok := v.validTagMap[""]; ok {
+		if attr, ok := gAttrs[attrName]; ok && attr.Context != contextUnset {
+			return attr.Context
+		}
+	}
+
+	return inferAttributeContext(attrName)
+}
+
+func (v *Validator) validateAttributeContext(tagName string, attrName string, attrValue string) ValidationError {
+	if !v.StrictSecurity {
+		return nil
+	}
+
+	switch v.contextFor(tagName, attrName) {
+	case URLContext:
+		return v.checkURLContext(tagName, attrName, attrValue)
+	case JSHandlerContext:
+		return v.checkJSHandlerContext(tagName, attrName, attrValue)
+	case CSSContext:
+		return v.checkCSSContext(tagName, attrName, attrValue)
+	}
+	return nil
+}
+
+// stripURLWhitespace removes ASCII tab and newline characters from anywhere
+// in the string, mirroring the WHATWG URL spec's "remove all ASCII tab or
+// newline" step. Browsers apply this before looking at a URL's scheme, so a
+// blocked scheme like "javascript:" still matches even when split up by an
+// embedded tab/CR/LF (e.g. from an HTML-decoded `&#9;`) — whether it's
+// sitting directly in an href or tucked inside a CSS url(...).
+func stripURLWhitespace(s string) string {
+	return strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(s)
+}
+
+func (v *Validator) checkURLContext(tagName string, attrName string, attrValue string) ValidationError {
+	trimmed := strings.TrimSpace(stripURLWhitespace(attrValue))
+	lower := strings.ToLower(trimmed)
+
+	switch {
+	case strings.HasPrefix(lower, "javascript:"):
+		return newAttributeContextError(tagName, attrName, attrValue, URLContext, "javascript: URLs are not allowed")
+	case strings.HasPrefix(lower, "vbscript:"):
+		return newAttributeContextError(tagName, attrName, attrValue, URLContext, "vbscript: URLs are not allowed")
+	case strings.HasPrefix(lower, "data:") && !v.AllowDataURLs:
+		return newAttributeContextError(tagName, attrName, attrValue, URLContext, "data: URLs are not allowed")
+	}
+
+	if _, err := url.Parse(trimmed); err != nil {
+		return newAttributeContextError(tagName, attrName, attrValue, URLContext, "value does not parse as a URL")
+	}
+	return nil
+}
+
+func (v *Validator) checkJSHandlerContext(tagName string, attrName string, attrValue string) ValidationError {
+	if v.isJSHandlerAllowed(tagName, attrName) {
+		return nil
+	}
+	return newAttributeContextError(tagName, attrName, attrValue, JSHandlerContext, "inline event handlers are not allowed")
+}
+
+// unsafeCSSConstructs are substrings that let a CSS value escape into script
+// execution or pull in untrusted remote content.
+var unsafeCSSConstructs = []string{"expression(", "@import", "url(javascript:"}
+
+func (v *Validator) checkCSSContext(tagName string, attrName string, attrValue string) ValidationError {
+	lower := strings.ToLower(stripURLWhitespace(attrValue))
+	for _, construct := range unsafeCSSConstructs {
+		if strings.Contains(lower, construct) {
+			return newAttributeContextError(tagName, attrName, attrValue, CSSContext, fmt.Sprintf("unsafe CSS construct '%s'", construct))
+		}
+	}
+	return nil
+}
+
 func (v *Validator) checkAttributeRegex(tagName string, attrName string) bool {
 	tag := v.validTags[tagName]
 	if tag.AttrStartsWith != "" {
@@ -238,7 +484,7 @@ func (v *Validator) checkAttributeRegex(tagName string, attrName string) bool {
 	return false
 }
 
-func (v *Validator) ValidateHtmlString(str string) []error {
+func (v *Validator) ValidateHtmlString(str string) ValidationErrorList {
 	buffer := strings.NewReader(str)
 	errors := v.ValidateHtml(buffer)
 	return errors
@@ -256,136 +502,35 @@ func (v *Validator) checkErrorCallback(err ValidationError) error {
 	return err
 }
 
-func (v *Validator) ValidateHtml(r io.Reader) []error {
-	d := html.NewTokenizer(r)
-
-	errors := []error{}
-	parents := []string{}
-	var err error
-	for {
-		parents, err = v.checkToken(d, parents)
-		if err != nil {
-			if errorsPkg.As(err, &ErrInvEOF{}) {
-				break
-			}
-			errors = append(errors, err)
-			if v.StopAfterFirstError {
-				return errors
-			}
-		}
-	}
+// ValidateHtml runs Walk with a Visitor that buffers every reported error
+// (transformed through the legacy ErrorCallback, if one is registered) into
+// the returned slice, stopping early when StopAfterFirstError is set.
+func (v *Validator) ValidateHtml(r io.Reader) ValidationErrorList {
+	bv := &bufferingVisitor{v: v}
+	_ = v.Walk(r, bv)
+	return bv.errors
+}
 
-	err = v.checkParents(d, parents)
-	if err != nil {
-		errors = append(errors, err)
-	}
-	return errors
+// ancestorFrame records one open element on the parent stack: its tag name
+// plus its attributes, so structural rules can match on more than just tag
+// name (see structural_rules.go).
+type ancestorFrame struct {
+	Tag   string
+	Attrs map[string]string
 }
 
-func indexOf(arr []string, val string) int {
-	for i, k := range arr {
-		if k == val {
+func indexOfTag(frames []ancestorFrame, tagName string) int {
+	for i, f := range frames {
+		if f.Tag == tagName {
 			return i
 		}
 	}
 	return -1
 }
 
-func (v *Validator) checkParents(d *html.Tokenizer, parents []string) error {
-	for _, tagName := range parents {
-		if v.IsValidSelfClosingTag(tagName) {
-			continue
-		}
-
-		cError := v.checkErrorCallback(ErrInvNotProperlyClosed{ErrorDetails{TagName: tagName}})
-		if cError != nil {
-			return cError
-		}
-	}
-	return nil
-}
-
-func popLast(list []string) []string {
+func popLast[T any](list []T) []T {
 	if len(list) == 0 {
 		return list
 	}
 	return list[0 : len(list)-1]
 }
-
-func (v *Validator) checkToken(d *html.Tokenizer, parents []string) ([]string, error) {
-
-	tokenType := d.Next()
-
-	if tokenType == html.ErrorToken {
-		return parents, ErrInvEOF{}
-	}
-
-	token := d.Token()
-
-	if tokenType == html.EndTagToken ||
-		tokenType == html.StartTagToken ||
-		tokenType == html.SelfClosingTagToken {
-
-		tagName := token.Data
-
-		if !v.IsValidTag(tagName) {
-			cError := v.checkErrorCallback(ErrInvTag{ErrorDetails{TagName: tagName}})
-			if cError != nil {
-				return parents, cError
-			}
-		}
-
-		if token.Type == html.StartTagToken ||
-			token.Type == html.SelfClosingTagToken {
-			parents = append(parents, tagName)
-		}
-
-		attrs := map[string]bool{}
-
-		for _, attr := range token.Attr {
-			err := v.validateAttribute(tagName, attr.Key, attr.Val)
-			if err != nil {
-				cError := v.checkErrorCallback(err)
-				if cError != nil {
-					return parents, cError
-				}
-			}
-
-			_, ok := attrs[attr.Key]
-			if !ok {
-				attrs[attr.Key] = true
-			} else {
-				cError := v.checkErrorCallback(ErrInvDuplicatedAttribute{ErrorDetails{TagName: tagName, AttributeName: attr.Key, AttributeValue: attr.Val}})
-				if cError != nil {
-					return parents, cError
-				}
-			}
-		}
-
-		if token.Type == html.EndTagToken {
-			if len(parents) > 0 && parents[len(parents)-1] == tagName {
-				parents = popLast(parents)
-			} else if len(parents) == 0 ||
-				parents[len(parents)-1] != tagName {
-				index := indexOf(parents, tagName)
-				if index > -1 {
-					missingTagName := parents[len(parents)-1]
-					parents = parents[0:index]
-					if !v.IsValidSelfClosingTag(missingTagName) {
-						cError := v.checkErrorCallback(ErrInvNotProperlyClosed{ErrorDetails{TagName: tagName}})
-						if cError != nil {
-							return parents, cError
-						}
-					}
-				} else {
-					cError := v.checkErrorCallback(ErrInvClosedBeforeOpened{ErrorDetails{TagName: tagName}})
-					if cError != nil {
-						return parents, cError
-					}
-				}
-			}
-		}
-	}
-
-	return parents, nil
-}
@@ -162,6 +162,73 @@ func Test_Callback_DisableErrors(t *testing.T) {
 	assert.Empty(t, errors)
 }
 
+func Test_StrictSecurity(t *testing.T) {
+	sv := Validator{StrictSecurity: true}
+	sv.AddValidTag(ValidTag{
+		Name:  "a",
+		Attrs: []Attribute{{Name: "href"}, {Name: "onclick"}},
+	})
+	sv.AddValidTag(ValidTag{
+		Name:  "div",
+		Attrs: []Attribute{{Name: "style"}},
+	})
+	sv.AllowJSHandler("a", "onclick")
+
+	testCases := []struct {
+		desc           string
+		rawHTML        string
+		isValid        bool
+		expectedErrors []interface{}
+	}{
+		{"Valid URL", "<a href='https://example.com'></a>", true, nil},
+		{"Allowlisted JS handler", "<a onclick='doThing()'></a>", true, nil},
+		{"Safe CSS", "<div style='color: red'></div>", true, nil},
+
+		{"javascript: URL", "<a href='javascript:alert(1)'></a>", false, []interface{}{&ErrInvAttributeContext{}}},
+		{"javascript: URL split by embedded tab/newline", "<a href='jav\tascript:\nalert(1)'></a>", false, []interface{}{&ErrInvAttributeContext{}}},
+		{"data: URL", "<a href='data:text/html,x'></a>", false, []interface{}{&ErrInvAttributeContext{}}},
+		{"CSS expression", "<div style='width: expression(alert(1))'></div>", false, []interface{}{&ErrInvAttributeContext{}}},
+		{"CSS url(javascript:) split by embedded tab/newline", "<div style='background:url(jav\tascript:\nalert(1))'></div>", false, []interface{}{&ErrInvAttributeContext{}}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			_errors := sv.ValidateHtmlString(tC.rawHTML)
+			if tC.isValid {
+				assert.Len(t, _errors, 0)
+				return
+			}
+			assert.NotEmpty(t, _errors)
+			for _, expectedError := range tC.expectedErrors {
+				found := false
+				for _, err := range _errors {
+					if errors.As(err, expectedError) {
+						found = true
+					}
+				}
+				assert.True(t, found)
+			}
+		})
+	}
+
+	jsv := Validator{StrictSecurity: true}
+	jsv.AddValidTag(ValidTag{Name: "a", Attrs: []Attribute{{Name: "onclick"}}})
+	jsErrors := jsv.ValidateHtmlString("<a onclick='doThing()'></a>")
+	assert.NotEmpty(t, jsErrors)
+	assert.True(t, errors.As(jsErrors[0], &ErrInvAttributeContext{}))
+}
+
+func Test_StrictSecurity_GlobalContextOverride(t *testing.T) {
+	gv := Validator{StrictSecurity: true}
+	gv.AddValidTag(ValidTag{
+		Name:  "",
+		Attrs: []Attribute{{Name: "href", Context: PlainContext}},
+	})
+	gv.AddValidTag(ValidTag{Name: "a", Attrs: []Attribute{{Name: "href"}}})
+
+	errs := gv.ValidateHtmlString("<a href='javascript:alert(1)'></a>")
+	assert.Empty(t, errs)
+}
+
 func BenchmarkValidateHtmlString(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		v.ValidateHtmlString("<b></b>\n<b></b>\n<b kkk='kkk'></b>")
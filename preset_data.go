@@ -0,0 +1,179 @@
+package htmlcheck
+
+// html5GlobalGroup holds the attributes valid on (almost) every HTML5
+// element, shared by the presets below.
+var html5GlobalGroup = &TagGroup{
+	Name: "html5-global",
+	Attrs: []Attribute{
+		{Name: "id"}, {Name: "class"}, {Name: "title"}, {Name: "lang"},
+		{Name: "dir"}, {Name: "tabindex"}, {Name: "hidden"},
+	},
+}
+
+var html5Tags = []*ValidTag{
+	{Name: "", AttrStartsWith: "data-"},
+	{Name: "html", Groups: []string{"html5-global"}},
+	{Name: "head", Groups: []string{"html5-global"}},
+	{Name: "body", Groups: []string{"html5-global"}},
+	{Name: "title", Groups: []string{"html5-global"}},
+	{Name: "meta", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "charset"}, {Name: "name"}, {Name: "content"}}, IsSelfClosing: true},
+	{Name: "link", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "rel"}, {Name: "href"}, {Name: "type"}}, IsSelfClosing: true},
+	{Name: "script", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "src"}, {Name: "type"}, {Name: "async"}, {Name: "defer"}}},
+	{Name: "style", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "type"}}},
+	{Name: "div", Groups: []string{"html5-global"}},
+	{Name: "span", Groups: []string{"html5-global"}},
+	{Name: "p", Groups: []string{"html5-global"}},
+	{Name: "a", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "href"}, {Name: "target"}, {Name: "rel"}}},
+	{Name: "img", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "src"}, {Name: "alt"}, {Name: "width"}, {Name: "height"}}, IsSelfClosing: true},
+	{Name: "ul", Groups: []string{"html5-global"}},
+	{Name: "ol", Groups: []string{"html5-global"}},
+	{Name: "li", Groups: []string{"html5-global"}},
+	{Name: "table", Groups: []string{"html5-global"}},
+	{Name: "thead", Groups: []string{"html5-global"}},
+	{Name: "tbody", Groups: []string{"html5-global"}},
+	{Name: "tr", Groups: []string{"html5-global"}},
+	{Name: "td", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "colspan"}, {Name: "rowspan"}}},
+	{Name: "th", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "colspan"}, {Name: "rowspan"}}},
+	{Name: "h1", Groups: []string{"html5-global"}},
+	{Name: "h2", Groups: []string{"html5-global"}},
+	{Name: "h3", Groups: []string{"html5-global"}},
+	{Name: "h4", Groups: []string{"html5-global"}},
+	{Name: "h5", Groups: []string{"html5-global"}},
+	{Name: "h6", Groups: []string{"html5-global"}},
+	{Name: "br", Groups: []string{"html5-global"}, IsSelfClosing: true},
+	{Name: "hr", Groups: []string{"html5-global"}, IsSelfClosing: true},
+	{Name: "strong", Groups: []string{"html5-global"}},
+	{Name: "em", Groups: []string{"html5-global"}},
+	{Name: "b", Groups: []string{"html5-global"}},
+	{Name: "i", Groups: []string{"html5-global"}},
+	{Name: "u", Groups: []string{"html5-global"}},
+	{Name: "blockquote", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "cite"}}},
+	{Name: "code", Groups: []string{"html5-global"}},
+	{Name: "pre", Groups: []string{"html5-global"}},
+	{Name: "nav", Groups: []string{"html5-global"}},
+	{Name: "header", Groups: []string{"html5-global"}},
+	{Name: "footer", Groups: []string{"html5-global"}},
+	{Name: "main", Groups: []string{"html5-global"}},
+	{Name: "section", Groups: []string{"html5-global"}},
+	{Name: "article", Groups: []string{"html5-global"}},
+	{Name: "aside", Groups: []string{"html5-global"}},
+	{Name: "figure", Groups: []string{"html5-global"}},
+	{Name: "figcaption", Groups: []string{"html5-global"}},
+	{Name: "small", Groups: []string{"html5-global"}},
+	{Name: "sub", Groups: []string{"html5-global"}},
+	{Name: "sup", Groups: []string{"html5-global"}},
+}
+
+var html5Preset = &Preset{
+	Name:   "html5",
+	Groups: []*TagGroup{html5GlobalGroup},
+	Tags:   html5Tags,
+}
+
+var html5FormsTags = []*ValidTag{
+	{Name: "form", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "action"}, {Name: "method"}, {Name: "enctype"}}},
+	{Name: "input", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "type"}, {Name: "name"}, {Name: "value"}, {Name: "placeholder"}, {Name: "required"}, {Name: "disabled"}}, IsSelfClosing: true},
+	{Name: "textarea", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "name"}, {Name: "rows"}, {Name: "cols"}, {Name: "placeholder"}}},
+	{Name: "select", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "name"}, {Name: "multiple"}}},
+	{Name: "option", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "value"}, {Name: "selected"}}},
+	{Name: "optgroup", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "label"}}},
+	{Name: "button", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "type"}, {Name: "disabled"}}},
+	{Name: "label", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "for"}}},
+	{Name: "fieldset", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "disabled"}}},
+	{Name: "legend", Groups: []string{"html5-global"}},
+	{Name: "datalist", Groups: []string{"html5-global"}},
+	{Name: "output", Groups: []string{"html5-global"}, Attrs: []Attribute{{Name: "for"}, {Name: "name"}}},
+}
+
+var html5FormsPreset = &Preset{
+	Name:   "html5-forms",
+	Groups: []*TagGroup{html5GlobalGroup},
+	Tags:   html5FormsTags,
+}
+
+// ariaGroup holds the WAI-ARIA attributes this preset recognizes. It's
+// applied to the elements ARIA is commonly attached to; other presets can
+// opt a tag in by giving it Groups: []string{"aria"} too.
+var ariaGroup = &TagGroup{
+	Name: "aria",
+	Attrs: []Attribute{
+		{Name: "role"},
+		{Name: "aria-label"}, {Name: "aria-labelledby"}, {Name: "aria-describedby"},
+		{Name: "aria-hidden"}, {Name: "aria-live"}, {Name: "aria-atomic"},
+		{Name: "aria-expanded"}, {Name: "aria-controls"}, {Name: "aria-current"},
+		{Name: "aria-disabled"}, {Name: "aria-pressed"}, {Name: "aria-selected"},
+		{Name: "aria-checked"}, {Name: "aria-haspopup"}, {Name: "aria-modal"},
+	},
+}
+
+var ariaPreset = &Preset{
+	Name:   "aria",
+	Groups: []*TagGroup{ariaGroup},
+	Tags: []*ValidTag{
+		{Name: "div", Groups: []string{"aria"}},
+		{Name: "span", Groups: []string{"aria"}},
+		{Name: "a", Groups: []string{"aria"}},
+		{Name: "button", Groups: []string{"aria"}},
+		{Name: "nav", Groups: []string{"aria"}},
+		{Name: "ul", Groups: []string{"aria"}},
+		{Name: "li", Groups: []string{"aria"}},
+		{Name: "input", Groups: []string{"aria"}},
+		{Name: "section", Groups: []string{"aria"}},
+		{Name: "header", Groups: []string{"aria"}},
+		{Name: "footer", Groups: []string{"aria"}},
+		{Name: "dialog", Groups: []string{"aria"}},
+	},
+}
+
+var svgInlinePreset = &Preset{
+	Name: "svg-inline",
+	Tags: []*ValidTag{
+		{Name: "svg", Attrs: []Attribute{{Name: "xmlns"}, {Name: "viewbox"}, {Name: "width"}, {Name: "height"}, {Name: "fill"}}},
+		{Name: "g", Attrs: []Attribute{{Name: "transform"}, {Name: "fill"}}},
+		{Name: "path", Attrs: []Attribute{{Name: "d"}, {Name: "fill"}, {Name: "stroke"}}, IsSelfClosing: true},
+		{Name: "circle", Attrs: []Attribute{{Name: "cx"}, {Name: "cy"}, {Name: "r"}, {Name: "fill"}}, IsSelfClosing: true},
+		{Name: "rect", Attrs: []Attribute{{Name: "x"}, {Name: "y"}, {Name: "width"}, {Name: "height"}, {Name: "fill"}}, IsSelfClosing: true},
+		{Name: "line", Attrs: []Attribute{{Name: "x1"}, {Name: "y1"}, {Name: "x2"}, {Name: "y2"}, {Name: "stroke"}}, IsSelfClosing: true},
+		{Name: "polygon", Attrs: []Attribute{{Name: "points"}, {Name: "fill"}}, IsSelfClosing: true},
+		{Name: "defs"},
+		{Name: "use", Attrs: []Attribute{{Name: "href"}}, IsSelfClosing: true},
+	},
+}
+
+var mathMLPreset = &Preset{
+	Name: "mathml",
+	Tags: []*ValidTag{
+		{Name: "math", Attrs: []Attribute{{Name: "xmlns"}, {Name: "display"}}},
+		{Name: "mrow"},
+		{Name: "mi"},
+		{Name: "mn"},
+		{Name: "mo"},
+		{Name: "mfrac"},
+		{Name: "msup"},
+		{Name: "msub"},
+		{Name: "msqrt"},
+		{Name: "mtext"},
+	},
+}
+
+// safeEmbedPreset is a deliberately small, locked-down subset intended for
+// rendering user-generated content: inline text formatting and links only,
+// nothing that can load remote content or execute script.
+var safeEmbedPreset = &Preset{
+	Name: "safe-embed",
+	Tags: []*ValidTag{
+		{Name: "p"},
+		{Name: "br", IsSelfClosing: true},
+		{Name: "b"},
+		{Name: "i"},
+		{Name: "u"},
+		{Name: "strong"},
+		{Name: "em"},
+		{Name: "code"},
+		{Name: "blockquote"},
+		{Name: "ul"},
+		{Name: "ol"},
+		{Name: "li"},
+		{Name: "a", Attrs: []Attribute{{Name: "href", Value: &AttributeValue{Regex: "^https?://"}}, {Name: "rel"}}},
+	},
+}
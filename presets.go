@@ -0,0 +1,47 @@
+package htmlcheck
+
+import "fmt"
+
+// Preset bundles a set of tags and groups that can be registered on a
+// Validator in one call instead of redeclaring them by hand. See
+// RegisterPreset and Validator.UsePreset.
+type Preset struct {
+	Name   string
+	Tags   []*ValidTag
+	Groups []*TagGroup
+}
+
+var presetRegistry = map[string]*Preset{}
+
+// RegisterPreset makes a preset available to UsePreset under p.Name,
+// overwriting any existing preset with the same name. This is how the
+// built-in presets (html5, html5-forms, aria, svg-inline, mathml,
+// safe-embed) are registered, and the same function lets callers add or
+// override their own.
+func RegisterPreset(p *Preset) {
+	presetRegistry[p.Name] = p
+}
+
+// UsePreset loads a registered preset's groups and tags into the Validator.
+// Presets compose: calling UsePreset more than once merges each preset's
+// tags and groups into the validator, so a later preset can extend a tag
+// added by an earlier one via Groups.
+func (v *Validator) UsePreset(name string) error {
+	preset, ok := presetRegistry[name]
+	if !ok {
+		return fmt.Errorf("htmlcheck: unknown preset '%s'", name)
+	}
+
+	v.AddGroups(preset.Groups)
+	v.AddValidTags(preset.Tags)
+	return nil
+}
+
+func init() {
+	RegisterPreset(html5Preset)
+	RegisterPreset(html5FormsPreset)
+	RegisterPreset(ariaPreset)
+	RegisterPreset(svgInlinePreset)
+	RegisterPreset(mathMLPreset)
+	RegisterPreset(safeEmbedPreset)
+}
@@ -0,0 +1,70 @@
+package htmlcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Presets(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		presets []string
+		rawHTML string
+		isValid bool
+	}{
+		{"html5 valid", []string{"html5"}, "<div id='main' data-test='x'><p>hello</p></div>", true},
+		{"html5 unknown tag", []string{"html5"}, "<foo></foo>", false},
+		{"html5-forms valid", []string{"html5", "html5-forms"}, "<form action='/submit'><input type='text' name='q'><button type='submit'></button></form>", true},
+		{"html5-forms standalone rejects non-form tags", []string{"html5-forms"}, "<div></div>", false},
+		{"aria composes with html5", []string{"html5", "aria"}, "<button role='button' aria-pressed='true'></button>", true},
+		{"aria rejects unknown aria attr", []string{"html5", "aria"}, "<button aria-bogus='true'></button>", false},
+		{"svg-inline valid", []string{"svg-inline"}, "<svg viewbox='0 0 10 10'><circle cx='5' cy='5' r='4'></circle></svg>", true},
+		{"mathml valid", []string{"mathml"}, "<math><mi>x</mi></math>", true},
+		{"safe-embed allows formatting and safe links", []string{"safe-embed"}, "<p><strong>hi</strong> <a href='https://example.com'>link</a></p>", true},
+		{"safe-embed rejects script", []string{"safe-embed"}, "<script>alert(1)</script>", false},
+		{"safe-embed rejects javascript href", []string{"safe-embed"}, "<a href='javascript:alert(1)'></a>", false},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			pv := Validator{}
+			for _, preset := range tC.presets {
+				err := pv.UsePreset(preset)
+				assert.NoError(t, err)
+			}
+
+			errs := pv.ValidateHtmlString(tC.rawHTML)
+			if tC.isValid {
+				assert.Empty(t, errs)
+			} else {
+				assert.NotEmpty(t, errs)
+			}
+		})
+	}
+}
+
+func Test_Preset_Composition_MergesGroups(t *testing.T) {
+	pv := Validator{}
+	assert.NoError(t, pv.UsePreset("html5"))
+	assert.NoError(t, pv.UsePreset("aria"))
+
+	// Re-registering "html5-global" with an extra attribute should backfill
+	// every tag still carrying that group — including "div", which aria's
+	// own registration also touches. If composing the two presets had
+	// replaced "div"'s *ValidTag instead of merging its Groups, "div" would
+	// have lost its html5-global membership here.
+	pv.AddGroup(&TagGroup{
+		Name:  "html5-global",
+		Attrs: append(append([]Attribute{}, html5GlobalGroup.Attrs...), Attribute{Name: "itemprop"}),
+	})
+
+	errs := pv.ValidateHtmlString("<div itemprop='x' role='button'></div>")
+	assert.Empty(t, errs)
+}
+
+func Test_UsePreset_Unknown(t *testing.T) {
+	pv := Validator{}
+	err := pv.UsePreset("does-not-exist")
+	assert.Error(t, err)
+}
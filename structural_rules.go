@@ -0,0 +1,260 @@
+package htmlcheck
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// StructuralRule constrains where an element is allowed to appear in the
+// document tree, independent of its own tag/attribute rules. Selector is a
+// minimal CSS-like selector (tag, #id, .class, [attr], [attr=val],
+// descendant ` ` and child `>` combinators) matched against the element
+// being opened and its ancestor chain.
+type StructuralRule struct {
+	Selector       string
+	RequireAttrs   []string
+	ForbidAttrs    []string
+	MaxDepth       int
+	AllowedParents []string
+}
+
+// AddStructuralRule compiles r.Selector and registers the rule, so it's
+// evaluated against every element StrictSecurity or not. Compiled
+// selectors are cached on the Validator, so registering several rules that
+// share a selector only compiles it once.
+func (v *Validator) AddStructuralRule(r StructuralRule) error {
+	if v.selectorCache == nil {
+		v.selectorCache = map[string]*compiledSelector{}
+	}
+
+	cs, ok := v.selectorCache[r.Selector]
+	if !ok {
+		compiled, err := compileSelector(r.Selector)
+		if err != nil {
+			return err
+		}
+		cs = compiled
+		v.selectorCache[r.Selector] = cs
+	}
+
+	v.structuralRules = append(v.structuralRules, &compiledStructuralRule{rule: r, selector: cs})
+	return nil
+}
+
+// structuralViolations evaluates every registered StructuralRule whose
+// selector matches the element (tagName, attrs) given its ancestor chain,
+// returning one ValidationError per rule it fails. It has no side effects;
+// its caller, Walk, reports each violation to the Visitor.
+func (v *Validator) structuralViolations(tagName string, attrs map[string]string, ancestors []ancestorFrame) []ValidationError {
+	var violations []ValidationError
+	for _, cr := range v.structuralRules {
+		if !cr.selector.matches(ancestors) {
+			continue
+		}
+		if err := cr.violation(tagName, attrs, ancestors); err != nil {
+			violations = append(violations, err)
+		}
+	}
+	return violations
+}
+
+type compiledStructuralRule struct {
+	rule     StructuralRule
+	selector *compiledSelector
+}
+
+func (cr *compiledStructuralRule) violation(tagName string, attrs map[string]string, ancestors []ancestorFrame) ValidationError {
+	r := cr.rule
+
+	for _, required := range r.RequireAttrs {
+		if _, ok := attrs[required]; !ok {
+			return newStructuralRuleError(tagName, r.Selector, fmt.Sprintf("missing required attribute '%s'", required))
+		}
+	}
+
+	for _, forbidden := range r.ForbidAttrs {
+		if _, ok := attrs[forbidden]; ok {
+			return newStructuralRuleError(tagName, r.Selector, fmt.Sprintf("forbidden attribute '%s' present", forbidden))
+		}
+	}
+
+	if r.MaxDepth > 0 {
+		if depth := len(ancestors) - 1; depth > r.MaxDepth {
+			return newStructuralRuleError(tagName, r.Selector, fmt.Sprintf("nested deeper than max depth %d", r.MaxDepth))
+		}
+	}
+
+	if len(r.AllowedParents) > 0 {
+		parentTag := ""
+		if len(ancestors) >= 2 {
+			parentTag = ancestors[len(ancestors)-2].Tag
+		}
+		if !slices.Contains(r.AllowedParents, parentTag) {
+			return newStructuralRuleError(tagName, r.Selector, fmt.Sprintf("not allowed as a child of '%s'", parentTag))
+		}
+	}
+
+	return nil
+}
+
+// selectorStep is one compound selector (tag + #id + .class* + [attr]*)
+// in a compiled selector, along with the combinator that precedes it.
+type selectorStep struct {
+	tag           string
+	id            string
+	classes       []string
+	attrs         map[string]string
+	attrsPresence []string
+	child         bool // true if joined to the previous step with '>' rather than a descendant space
+}
+
+type compiledSelector struct {
+	steps []*selectorStep
+}
+
+// matches reports whether the selector matches the last frame in ancestors
+// (the element currently being opened), using the rest of the slice as its
+// ancestor chain.
+func (cs *compiledSelector) matches(ancestors []ancestorFrame) bool {
+	if len(cs.steps) == 0 || len(ancestors) == 0 {
+		return false
+	}
+
+	lastStep := len(cs.steps) - 1
+	lastFrame := len(ancestors) - 1
+	if !stepMatchesFrame(cs.steps[lastStep], ancestors[lastFrame]) {
+		return false
+	}
+	return matchAncestorSteps(cs.steps, lastStep-1, ancestors, lastFrame-1)
+}
+
+func matchAncestorSteps(steps []*selectorStep, stepIdx int, ancestors []ancestorFrame, frameIdx int) bool {
+	if stepIdx < 0 {
+		return true
+	}
+
+	step := steps[stepIdx]
+	// The step after this one records whether it was joined to this one
+	// with '>', i.e. whether this step must match the immediate parent.
+	if steps[stepIdx+1].child {
+		if frameIdx < 0 || !stepMatchesFrame(step, ancestors[frameIdx]) {
+			return false
+		}
+		return matchAncestorSteps(steps, stepIdx-1, ancestors, frameIdx-1)
+	}
+
+	for i := frameIdx; i >= 0; i-- {
+		if stepMatchesFrame(step, ancestors[i]) && matchAncestorSteps(steps, stepIdx-1, ancestors, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func stepMatchesFrame(step *selectorStep, frame ancestorFrame) bool {
+	if step.tag != "" && step.tag != "*" && step.tag != frame.Tag {
+		return false
+	}
+	if step.id != "" && frame.Attrs["id"] != step.id {
+		return false
+	}
+	for _, class := range step.classes {
+		if !hasClass(frame.Attrs["class"], class) {
+			return false
+		}
+	}
+	for attr, val := range step.attrs {
+		if frame.Attrs[attr] != val {
+			return false
+		}
+	}
+	for _, attr := range step.attrsPresence {
+		if _, ok := frame.Attrs[attr]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func hasClass(classAttr string, class string) bool {
+	return slices.Contains(strings.Fields(classAttr), class)
+}
+
+var compoundPartRegex = regexp.MustCompile(`#[\w-]+|\.[\w-]+|\[[^\]]+\]`)
+
+func isNameByte(b byte) bool {
+	return b == '*' || b == '-' || b == '_' ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// compileSelector parses a minimal CSS-like selector into a compiledSelector.
+// Supported: tag names, #id, .class (repeatable), [attr] / [attr=value]
+// (repeatable), and the descendant (space) and child (>) combinators.
+func compileSelector(selector string) (*compiledSelector, error) {
+	tokens := tokenizeSelector(selector)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("htmlcheck: empty selector")
+	}
+
+	cs := &compiledSelector{}
+	childNext := false
+	for _, token := range tokens {
+		if token == ">" {
+			childNext = true
+			continue
+		}
+		step, err := compileCompound(token)
+		if err != nil {
+			return nil, err
+		}
+		step.child = childNext
+		childNext = false
+		cs.steps = append(cs.steps, step)
+	}
+
+	if len(cs.steps) == 0 {
+		return nil, fmt.Errorf("htmlcheck: selector '%s' has no steps", selector)
+	}
+	return cs, nil
+}
+
+func tokenizeSelector(selector string) []string {
+	normalized := strings.ReplaceAll(strings.TrimSpace(selector), ">", " > ")
+	return strings.Fields(normalized)
+}
+
+func compileCompound(token string) (*selectorStep, error) {
+	i := 0
+	for i < len(token) && isNameByte(token[i]) {
+		i++
+	}
+
+	step := &selectorStep{tag: token[:i], attrs: map[string]string{}}
+	rest := token[i:]
+
+	for _, part := range compoundPartRegex.FindAllString(rest, -1) {
+		switch part[0] {
+		case '#':
+			step.id = part[1:]
+		case '.':
+			step.classes = append(step.classes, part[1:])
+		case '[':
+			inner := strings.TrimSuffix(strings.TrimPrefix(part, "["), "]")
+			if eq := strings.Index(inner, "="); eq >= 0 {
+				key := strings.TrimSpace(inner[:eq])
+				val := strings.Trim(strings.TrimSpace(inner[eq+1:]), `"'`)
+				step.attrs[key] = val
+			} else {
+				step.attrsPresence = append(step.attrsPresence, strings.TrimSpace(inner))
+			}
+		}
+	}
+
+	if strings.TrimSpace(compoundPartRegex.ReplaceAllString(rest, "")) != "" {
+		return nil, fmt.Errorf("htmlcheck: invalid selector fragment '%s'", token)
+	}
+
+	return step, nil
+}
@@ -0,0 +1,90 @@
+package htmlcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStructuralTestValidator(t *testing.T) *Validator {
+	t.Helper()
+	sv := &Validator{}
+	sv.AddValidTag(ValidTag{Name: "", Attrs: []Attribute{{Name: "id"}, {Name: "class"}}, AttrStartsWith: "data-"})
+	sv.AddValidTag(ValidTag{Name: "html"})
+	sv.AddValidTag(ValidTag{Name: "head"})
+	sv.AddValidTag(ValidTag{Name: "body"})
+	sv.AddValidTag(ValidTag{Name: "script", Attrs: []Attribute{{Name: "src"}}})
+	sv.AddValidTag(ValidTag{Name: "a", Attrs: []Attribute{{Name: "href"}}})
+	sv.AddValidTag(ValidTag{Name: "div"})
+	sv.AddValidTag(ValidTag{Name: "img", Attrs: []Attribute{{Name: "src"}, {Name: "alt"}, {Name: "onerror"}}, IsSelfClosing: true})
+	return sv
+}
+
+func Test_StructuralRule_RequireAttrs(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{
+		Selector:     "a img",
+		RequireAttrs: []string{"alt"},
+	}))
+
+	assert.Empty(t, sv.ValidateHtmlString("<a href='/'><img src='x.png' alt='x'></a>"))
+	assert.NotEmpty(t, sv.ValidateHtmlString("<a href='/'><img src='x.png'></a>"))
+	assert.Empty(t, sv.ValidateHtmlString("<img src='x.png'>"))
+}
+
+func Test_StructuralRule_AllowedParents(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{
+		Selector:       "script",
+		AllowedParents: []string{"head"},
+	}))
+
+	assert.Empty(t, sv.ValidateHtmlString("<html><head><script src='a.js'></script></head></html>"))
+	errs := sv.ValidateHtmlString("<html><body><script src='a.js'></script></body></html>")
+	assert.NotEmpty(t, errs)
+	found := false
+	for _, e := range errs {
+		if _, ok := e.(ErrInvStructuralRule); ok {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func Test_StructuralRule_ChildCombinator(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{
+		Selector:    "body > img",
+		ForbidAttrs: []string{"onerror"},
+	}))
+
+	// img nested inside a div inside body isn't a direct child, so the
+	// selector (and thus the rule) shouldn't apply.
+	assert.Empty(t, sv.ValidateHtmlString("<html><body><div><img src='x.png' onerror='x'></div></body></html>"))
+}
+
+func Test_StructuralRule_MaxDepth(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{
+		Selector: "div",
+		MaxDepth: 1,
+	}))
+
+	assert.Empty(t, sv.ValidateHtmlString("<div></div>"))
+	assert.NotEmpty(t, sv.ValidateHtmlString("<div><div><div></div></div></div>"))
+}
+
+func Test_StructuralRule_SelectorCaching(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{Selector: "a img", RequireAttrs: []string{"alt"}}))
+	assert.NoError(t, sv.AddStructuralRule(StructuralRule{Selector: "a img", ForbidAttrs: []string{"onerror"}}))
+
+	assert.Len(t, sv.selectorCache, 1)
+	assert.Len(t, sv.structuralRules, 2)
+}
+
+func Test_StructuralRule_InvalidSelector(t *testing.T) {
+	sv := newStructuralTestValidator(t)
+	err := sv.AddStructuralRule(StructuralRule{Selector: "div[[bad"})
+	assert.Error(t, err)
+}
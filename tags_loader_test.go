@@ -0,0 +1,60 @@
+package htmlcheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleTagsJSON = `{
+	"groups": [{"name": "g", "attrs": [{"name": "id"}]}],
+	"tags": [{"name": "a", "attrs": [{"name": "href"}], "groups": ["g"]}]
+}`
+
+const sampleTagsYAML = `
+groups:
+  - name: g
+    attrs:
+      - name: id
+tags:
+  - name: a
+    attrs:
+      - name: href
+    groups: [g]
+`
+
+func Test_LoadTagsFromString(t *testing.T) {
+	jv := Validator{}
+	assert.NoError(t, jv.LoadTagsFromString(sampleTagsJSON, "json"))
+	assert.Empty(t, jv.ValidateHtmlString("<a href='x' id='y'></a>"))
+	assert.NotEmpty(t, jv.ValidateHtmlString("<a hrefff='x'></a>"))
+
+	yv := Validator{}
+	assert.NoError(t, yv.LoadTagsFromString(sampleTagsYAML, "yaml"))
+	assert.Empty(t, yv.ValidateHtmlString("<a href='x' id='y'></a>"))
+	assert.NotEmpty(t, yv.ValidateHtmlString("<a hrefff='x'></a>"))
+}
+
+func Test_LoadTagsFromString_UnsupportedFormat(t *testing.T) {
+	v := Validator{}
+	err := v.LoadTagsFromString(sampleTagsJSON, "toml")
+	assert.Error(t, err)
+}
+
+func Test_LoadTagsFromFile_DetectsFormatFromExtension(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "tags.json")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(sampleTagsJSON), 0o644))
+	jv := Validator{}
+	assert.NoError(t, jv.LoadTagsFromFile(jsonPath))
+	assert.Empty(t, jv.ValidateHtmlString("<a href='x'></a>"))
+
+	yamlPath := filepath.Join(dir, "tags.yaml")
+	assert.NoError(t, os.WriteFile(yamlPath, []byte(sampleTagsYAML), 0o644))
+	yv := Validator{}
+	assert.NoError(t, yv.LoadTagsFromFile(yamlPath))
+	assert.Empty(t, yv.ValidateHtmlString("<a href='x'></a>"))
+}
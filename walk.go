@@ -0,0 +1,221 @@
+package htmlcheck
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// Action tells Walk how to proceed after a Visitor callback.
+type Action int
+
+const (
+	Continue Action = iota // keep walking normally
+	Skip                   // don't descend into this element's children
+	Stop                   // abort the walk immediately
+)
+
+// Visitor receives push-based events from Walk, so large documents can be
+// validated without accumulating a []error and callers can short-circuit
+// by returning Stop (or Skip a subtree) from any callback.
+type Visitor interface {
+	OnStart(tag string, attrs []html.Attribute, depth int) Action
+	OnEnd(tag string, depth int) Action
+	OnError(err ValidationError) Action
+}
+
+// Walk tokenizes r and reports start tags, end tags and validation errors
+// to v as it goes, rather than buffering them into a slice. It honors
+// Validator.MaxTokens and Validator.MaxDepth, aborting with
+// ErrInvLimitExceeded if either is exceeded.
+func (v *Validator) Walk(r io.Reader, vis Visitor) error {
+	d := html.NewTokenizer(r)
+
+	parents := []ancestorFrame{}
+	tokenCount := 0
+	skipUntilLen := -1 // -1 means not skipping a subtree; otherwise the parents length to skip down to
+
+	for {
+		tokenType := d.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+
+		tokenCount++
+		if v.MaxTokens > 0 && tokenCount > v.MaxTokens {
+			err := newLimitExceededError(fmt.Sprintf("document exceeds MaxTokens (%d)", v.MaxTokens))
+			vis.OnError(err)
+			return err
+		}
+
+		token := d.Token()
+		if tokenType != html.StartTagToken && tokenType != html.EndTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+
+		tagName := token.Data
+		skipping := skipUntilLen >= 0 && len(parents) >= skipUntilLen
+
+		if v.MaxDepth > 0 && len(parents) > v.MaxDepth {
+			err := newLimitExceededError(fmt.Sprintf("document exceeds MaxDepth (%d)", v.MaxDepth))
+			vis.OnError(err)
+			return err
+		}
+
+		if !skipping && !v.IsValidTag(tagName) {
+			if vis.OnError(ErrInvTag{ErrorDetails{TagName: tagName}}) == Stop {
+				return nil
+			}
+		}
+
+		attrValues := map[string]string{}
+		for _, attr := range token.Attr {
+			attrValues[attr.Key] = attr.Val
+		}
+
+		if tokenType == html.StartTagToken || tokenType == html.SelfClosingTagToken {
+			parents = append(parents, ancestorFrame{Tag: tagName, Attrs: attrValues})
+		}
+
+		if !skipping {
+			if action := v.reportAttributes(vis, tagName, token.Attr); action == Stop {
+				return nil
+			}
+
+			if tokenType == html.StartTagToken || tokenType == html.SelfClosingTagToken {
+				for _, violation := range v.structuralViolations(tagName, attrValues, parents) {
+					if vis.OnError(violation) == Stop {
+						return nil
+					}
+				}
+			}
+		}
+
+		switch tokenType {
+		case html.StartTagToken:
+			action := Continue
+			if !skipping {
+				action = vis.OnStart(tagName, token.Attr, len(parents)-1)
+			}
+			if action == Stop {
+				return nil
+			}
+			if action == Skip && skipUntilLen < 0 {
+				skipUntilLen = len(parents)
+			}
+
+		case html.SelfClosingTagToken:
+			if !skipping {
+				if vis.OnStart(tagName, token.Attr, len(parents)-1) == Stop {
+					return nil
+				}
+			}
+			// no children to walk, so this element closes immediately
+			parents = popLast(parents)
+			if skipUntilLen >= 0 && len(parents) < skipUntilLen {
+				skipUntilLen = -1
+			}
+
+		case html.EndTagToken:
+			depth := len(parents) - 1
+			if len(parents) > 0 && parents[len(parents)-1].Tag == tagName {
+				parents = popLast(parents)
+			} else if len(parents) == 0 || parents[len(parents)-1].Tag != tagName {
+				index := indexOfTag(parents, tagName)
+				if index > -1 {
+					missingTagName := parents[len(parents)-1].Tag
+					parents = parents[0:index]
+					if !skipping && !v.IsValidSelfClosingTag(missingTagName) {
+						if vis.OnError(ErrInvNotProperlyClosed{ErrorDetails{TagName: tagName}}) == Stop {
+							return nil
+						}
+					}
+				} else if !skipping {
+					if vis.OnError(ErrInvClosedBeforeOpened{ErrorDetails{TagName: tagName}}) == Stop {
+						return nil
+					}
+				}
+			}
+
+			exitingSkip := skipUntilLen >= 0 && len(parents) < skipUntilLen
+			if exitingSkip {
+				skipUntilLen = -1
+			}
+			// Skip only suppresses descending into an element's children, not
+			// the element's own End event, so the closing tag that ends a
+			// skipped subtree (the skipped element itself) still reports.
+			if !skipping || exitingSkip {
+				if vis.OnEnd(tagName, depth) == Stop {
+					return nil
+				}
+			}
+		}
+	}
+
+	for _, frame := range parents {
+		if v.IsValidSelfClosingTag(frame.Tag) {
+			continue
+		}
+		if vis.OnError(ErrInvNotProperlyClosed{ErrorDetails{TagName: frame.Tag}}) == Stop {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) reportAttributes(vis Visitor, tagName string, tokenAttrs []html.Attribute) Action {
+	seen := map[string]bool{}
+	for _, attr := range tokenAttrs {
+		if err := v.validateAttribute(tagName, attr.Key, attr.Val); err != nil {
+			if vis.OnError(err) == Stop {
+				return Stop
+			}
+		}
+
+		if err := v.validateAttributeContext(tagName, attr.Key, attr.Val); err != nil {
+			if vis.OnError(err) == Stop {
+				return Stop
+			}
+		}
+
+		if seen[attr.Key] {
+			dupErr := ErrInvDuplicatedAttribute{ErrorDetails{TagName: tagName, AttributeName: attr.Key, AttributeValue: attr.Val}}
+			if vis.OnError(dupErr) == Stop {
+				return Stop
+			}
+		}
+		seen[attr.Key] = true
+	}
+	return Continue
+}
+
+// bufferingVisitor adapts Walk back to the original []error-returning
+// ValidateHtml API, including the legacy ErrorCallback/StopAfterFirstError
+// behavior.
+type bufferingVisitor struct {
+	v      *Validator
+	errors ValidationErrorList
+}
+
+func (b *bufferingVisitor) OnStart(tag string, attrs []html.Attribute, depth int) Action {
+	return Continue
+}
+
+func (b *bufferingVisitor) OnEnd(tag string, depth int) Action {
+	return Continue
+}
+
+func (b *bufferingVisitor) OnError(err ValidationError) Action {
+	cError := b.v.checkErrorCallback(err)
+	if cError == nil {
+		return Continue
+	}
+
+	b.errors = append(b.errors, cError)
+	if b.v.StopAfterFirstError {
+		return Stop
+	}
+	return Continue
+}
@@ -0,0 +1,118 @@
+package htmlcheck
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+type recordingVisitor struct {
+	starts    []string
+	ends      []string
+	errors    []ValidationError
+	stopAfter int
+}
+
+func (r *recordingVisitor) OnStart(tag string, attrs []html.Attribute, depth int) Action {
+	r.starts = append(r.starts, tag)
+	return Continue
+}
+
+func (r *recordingVisitor) OnEnd(tag string, depth int) Action {
+	r.ends = append(r.ends, tag)
+	return Continue
+}
+
+func (r *recordingVisitor) OnError(err ValidationError) Action {
+	r.errors = append(r.errors, err)
+	if r.stopAfter > 0 && len(r.errors) >= r.stopAfter {
+		return Stop
+	}
+	return Continue
+}
+
+func Test_Walk_Basic(t *testing.T) {
+	rv := &recordingVisitor{}
+	err := v.Walk(strings.NewReader("<b><a href='x'></a></b>"), rv)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, rv.starts)
+	assert.Equal(t, []string{"a", "b"}, rv.ends)
+	assert.Empty(t, rv.errors)
+}
+
+func Test_Walk_Stop(t *testing.T) {
+	rv := &recordingVisitor{stopAfter: 1}
+	err := v.Walk(strings.NewReader("<kkk><asd></asd></kkk>"), rv)
+	assert.NoError(t, err)
+	assert.Len(t, rv.errors, 1)
+	assert.Empty(t, rv.starts)
+}
+
+type skipFirstVisitor struct {
+	target string
+	starts []string
+	ends   []string
+}
+
+func (s *skipFirstVisitor) OnStart(tag string, attrs []html.Attribute, depth int) Action {
+	s.starts = append(s.starts, tag)
+	if tag == s.target {
+		return Skip
+	}
+	return Continue
+}
+
+func (s *skipFirstVisitor) OnEnd(tag string, depth int) Action {
+	s.ends = append(s.ends, tag)
+	return Continue
+}
+
+func (s *skipFirstVisitor) OnError(err ValidationError) Action { return Continue }
+
+func Test_Walk_Skip(t *testing.T) {
+	sv := &skipFirstVisitor{target: "b"}
+	err := v.Walk(strings.NewReader("<b><a href='x'></a></b><c></c>"), sv)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, sv.starts)
+	// Skip only suppresses descending into "b"'s children ("a" never shows
+	// up here either), not "b"'s own End event.
+	assert.Equal(t, []string{"b", "c"}, sv.ends)
+}
+
+func Test_Walk_MaxTokens(t *testing.T) {
+	lv := Validator{MaxTokens: 2}
+	lv.AddValidTag(ValidTag{Name: "a"})
+	rv := &recordingVisitor{}
+
+	err := lv.Walk(strings.NewReader("<a></a><a></a>"), rv)
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, &ErrInvLimitExceeded{}))
+	assert.NotEmpty(t, rv.errors)
+}
+
+func Test_Walk_MaxDepth(t *testing.T) {
+	lv := Validator{MaxDepth: 1}
+	lv.AddValidTag(ValidTag{Name: "div"})
+	rv := &recordingVisitor{}
+
+	err := lv.Walk(strings.NewReader("<div><div><div></div></div></div>"), rv)
+	assert.Error(t, err)
+	assert.True(t, errors.As(err, &ErrInvLimitExceeded{}))
+}
+
+func Test_ValidateHtml_StillBuffersErrors(t *testing.T) {
+	lv := Validator{}
+	lv.AddValidTag(ValidTag{Name: "b"})
+	errs := lv.ValidateHtmlString("<b><asd></asd></b>")
+	assert.NotEmpty(t, errs)
+	found := false
+	for _, e := range errs {
+		if errors.As(e, &ErrInvTag{}) {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}